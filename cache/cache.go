@@ -0,0 +1,184 @@
+// Package cache is a size-capped, disk-backed cache of byte blobs keyed
+// by an opaque string key, written atomically via write-then-rename.
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Cache stores byte blobs under dir, evicting the least recently used
+// entry once the total size of cached blobs exceeds maxSize.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu    sync.Mutex
+	lru   *list.List
+	items map[string]*list.Element
+	size  int64
+}
+
+type entry struct {
+	key  string
+	size int64
+}
+
+// New opens (or creates) a cache rooted at dir. A maxSize of 0 disables
+// eviction.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:     dir,
+		maxSize: maxSize,
+		lru:     list.New(),
+		items:   make(map[string]*list.Element),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Key derives a cache key from a session ID and the prompt that would
+// be sent against it, so identical follow-ups hit the same entry.
+func Key(sessionID, prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return sessionID + "-" + hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) load() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		// A .tmp file is a write that never got renamed into place (e.g.
+		// the process crashed between WriteFile and Rename in put); it's
+		// not a valid entry, so drop it rather than counting it against
+		// maxSize forever.
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			os.Remove(filepath.Join(c.dir, e.Name()))
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		c.touch(e.Name(), info.Size())
+	}
+	return nil
+}
+
+// GetOrCreate returns the cached bytes for key and whether it was
+// already cached (a hit), calling fn to produce and cache them on a
+// miss. Callers that have a one-time side effect to perform only on a
+// genuine miss (e.g. appending a transcript) should key it off the
+// returned hit bool.
+func (c *Cache) GetOrCreate(key string, fn func() (io.ReadCloser, error)) (data []byte, hit bool, err error) {
+	if data, ok := c.get(key); ok {
+		return data, true, nil
+	}
+
+	rc, err := fn()
+	if err != nil {
+		return nil, false, err
+	}
+	defer rc.Close()
+
+	data, err = io.ReadAll(rc)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := c.put(key, data); err != nil {
+		return nil, false, err
+	}
+
+	return data, false, nil
+}
+
+func (c *Cache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	el, ok := c.items[key]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.dir, key))
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	c.lru.MoveToFront(el)
+	c.mu.Unlock()
+
+	return data, true
+}
+
+// put atomically writes data for key (write to a temp file, then
+// rename) and evicts old entries until the cache fits within maxSize.
+func (c *Cache) put(key string, data []byte) error {
+	path := filepath.Join(c.dir, key)
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	c.touch(key, int64(len(data)))
+	c.evict()
+	return nil
+}
+
+func (c *Cache) touch(key string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		ent := el.Value.(*entry)
+		c.size += size - ent.size
+		ent.size = size
+		c.lru.MoveToFront(el)
+		return
+	}
+
+	el := c.lru.PushFront(&entry{key: key, size: size})
+	c.items[key] = el
+	c.size += size
+}
+
+func (c *Cache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.maxSize > 0 && c.size > c.maxSize {
+		back := c.lru.Back()
+		if back == nil {
+			return
+		}
+		ent := back.Value.(*entry)
+		c.lru.Remove(back)
+		delete(c.items, ent.key)
+		c.size -= ent.size
+		os.Remove(filepath.Join(c.dir, ent.key))
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func blob(s string) func() (io.ReadCloser, error) {
+	calls := 0
+	return func() (io.ReadCloser, error) {
+		calls++
+		if calls > 1 {
+			panic("fn called more than once for the same key")
+		}
+		return io.NopCloser(strings.NewReader(s)), nil
+	}
+}
+
+func TestGetOrCreate(t *testing.T) {
+	c, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	data, hit, err := c.GetOrCreate("key", blob("hello"))
+	if err != nil {
+		t.Fatalf("GetOrCreate() error: %v", err)
+	}
+	if hit {
+		t.Fatal("GetOrCreate() hit = true on first call, want false")
+	}
+	if string(data) != "hello" {
+		t.Fatalf("GetOrCreate() data = %q, want %q", data, "hello")
+	}
+
+	data, hit, err = c.GetOrCreate("key", blob("should not run"))
+	if err != nil {
+		t.Fatalf("GetOrCreate() error: %v", err)
+	}
+	if !hit {
+		t.Fatal("GetOrCreate() hit = false on second call, want true")
+	}
+	if string(data) != "hello" {
+		t.Fatalf("GetOrCreate() data = %q, want %q", data, "hello")
+	}
+}
+
+func TestGetOrCreateEviction(t *testing.T) {
+	c, err := New(t.TempDir(), 10)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	if _, _, err := c.GetOrCreate("a", blob("0123456789")); err != nil {
+		t.Fatalf("GetOrCreate(a) error: %v", err)
+	}
+	if _, _, err := c.GetOrCreate("b", blob("9876543210")); err != nil {
+		t.Fatalf("GetOrCreate(b) error: %v", err)
+	}
+
+	// a should have been evicted to stay within maxSize, so fetching it
+	// again must recompute rather than hit.
+	_, hit, err := c.GetOrCreate("a", blob("0123456789"))
+	if err != nil {
+		t.Fatalf("GetOrCreate(a) error: %v", err)
+	}
+	if hit {
+		t.Fatal("GetOrCreate(a) hit = true, want the entry to have been evicted")
+	}
+}
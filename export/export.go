@@ -0,0 +1,39 @@
+package export
+
+import (
+	"fmt"
+	"time"
+)
+
+// Session is the subset of conductor session state an Exporter needs.
+// Callers adapt their own session records into this shape so this
+// package has no dependency on the caller's storage layer.
+type Session struct {
+	ID         string
+	Task       string
+	Status     string
+	Started    time.Time
+	Transcript []byte
+}
+
+// Exporter writes a set of sessions to dest in some format.
+type Exporter interface {
+	Export(sessions []Session, dest string) error
+}
+
+// New returns the Exporter registered for outputType (local, tar, json,
+// or markdown).
+func New(outputType string) (Exporter, error) {
+	switch outputType {
+	case "local":
+		return localExporter{}, nil
+	case "tar":
+		return tarExporter{}, nil
+	case "json":
+		return jsonExporter{}, nil
+	case "markdown", "md":
+		return markdownExporter{}, nil
+	default:
+		return nil, fmt.Errorf("export: unknown output type %q", outputType)
+	}
+}
@@ -0,0 +1,23 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// jsonExporter writes sessions (with their transcripts) as a single
+// structured JSON array. dest of "-" writes to stdout.
+type jsonExporter struct{}
+
+func (jsonExporter) Export(sessions []Session, dest string) error {
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dest == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(dest, data, 0o644)
+}
@@ -0,0 +1,24 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// localExporter writes one transcript file per session into dest.
+type localExporter struct{}
+
+func (localExporter) Export(sessions []Session, dest string) error {
+	if err := os.MkdirAll(dest, 0o755); err != nil {
+		return err
+	}
+
+	for _, s := range sessions {
+		path := filepath.Join(dest, s.ID+".log")
+		if err := os.WriteFile(path, s.Transcript, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
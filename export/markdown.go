@@ -0,0 +1,47 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// markdownExporter writes a human-readable report grouping sessions by
+// status. dest of "-" writes to stdout.
+type markdownExporter struct{}
+
+var statusOrder = []string{"running", "completed", "failed"}
+
+func (markdownExporter) Export(sessions []Session, dest string) error {
+	byStatus := make(map[string][]Session, len(statusOrder))
+	for _, s := range sessions {
+		byStatus[s.Status] = append(byStatus[s.Status], s)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Conductor session report\n\n")
+	for _, status := range statusOrder {
+		group := byStatus[status]
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "## %s (%d)\n\n", capitalize(status), len(group))
+		for _, s := range group {
+			fmt.Fprintf(&b, "- `%s` — %s\n", s.ID, s.Task)
+		}
+		b.WriteString("\n")
+	}
+
+	if dest == "-" {
+		_, err := os.Stdout.WriteString(b.String())
+		return err
+	}
+	return os.WriteFile(dest, []byte(b.String()), 0o644)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,56 @@
+// Package export writes conductor sessions and their transcripts out in
+// a handful of formats, selected by a BuildKit-style output spec.
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Spec is a parsed -o/--output value, e.g. "type=local,dest=./out".
+type Spec struct {
+	Type string
+	Dest string
+}
+
+// ParseSpec parses a -o/--output value into a Spec. It tolerates the
+// short forms docker buildkit accepts: a bare path implies type=local,
+// and a bare "-" implies type=tar written to stdout.
+func ParseSpec(raw string) (Spec, error) {
+	if raw == "" {
+		return Spec{}, fmt.Errorf("export: empty output spec")
+	}
+
+	if raw == "-" {
+		return Spec{Type: "tar", Dest: "-"}, nil
+	}
+
+	if !strings.Contains(raw, "=") {
+		return Spec{Type: "local", Dest: raw}, nil
+	}
+
+	var spec Spec
+	for _, field := range strings.Split(raw, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return Spec{}, fmt.Errorf("export: invalid output field %q", field)
+		}
+		switch kv[0] {
+		case "type":
+			spec.Type = kv[1]
+		case "dest":
+			spec.Dest = kv[1]
+		default:
+			return Spec{}, fmt.Errorf("export: unknown output key %q", kv[0])
+		}
+	}
+
+	if spec.Type == "" {
+		return Spec{}, fmt.Errorf("export: output spec %q missing type", raw)
+	}
+	if spec.Dest == "" {
+		return Spec{}, fmt.Errorf("export: output spec %q missing dest", raw)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,39 @@
+package export
+
+import "testing"
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    Spec
+		wantErr bool
+	}{
+		{name: "bare dash means tar to stdout", raw: "-", want: Spec{Type: "tar", Dest: "-"}},
+		{name: "bare path means local", raw: "./out", want: Spec{Type: "local", Dest: "./out"}},
+		{name: "full form", raw: "type=json,dest=sessions.json", want: Spec{Type: "json", Dest: "sessions.json"}},
+		{name: "full form order independent", raw: "dest=report.md,type=markdown", want: Spec{Type: "markdown", Dest: "report.md"}},
+		{name: "empty spec is an error", raw: "", wantErr: true},
+		{name: "missing dest is an error", raw: "type=json", wantErr: true},
+		{name: "missing type is an error", raw: "dest=out.json", wantErr: true},
+		{name: "unknown key is an error", raw: "type=json,bogus=1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSpec(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSpec(%q) err = nil, want error", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSpec(%q) unexpected error: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseSpec(%q) = %+v, want %+v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+)
+
+// tarExporter streams every session's transcript as a single tar
+// archive. dest of "-" writes to stdout.
+type tarExporter struct{}
+
+func (tarExporter) Export(sessions []Session, dest string) error {
+	var w io.Writer = os.Stdout
+	if dest != "-" {
+		f, err := os.Create(dest)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	for _, s := range sessions {
+		hdr := &tar.Header{
+			Name: s.ID + ".log",
+			Mode: 0o644,
+			Size: int64(len(s.Transcript)),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(s.Transcript); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/brittonhayes/claude-conductor/export"
+)
+
+// runExportCmd implements the `conductor export` subcommand.
+func runExportCmd(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var output string
+	fs.StringVar(&output, "o", "", "output spec, e.g. type=local,dest=./out")
+	fs.StringVar(&output, "output", "", "same as -o")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if output == "" {
+		return fmt.Errorf("export: -o/--output is required")
+	}
+
+	return runExport(output)
+}
+
+// runExport loads every known session and its transcript from
+// ~/.conductor and writes them out using the Exporter selected by
+// specRaw.
+func runExport(specRaw string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Join(home, ".conductor")
+	sessionDir := filepath.Join(baseDir, "sessions")
+	outputDir := filepath.Join(baseDir, "outputs")
+
+	store, err := NewStore(sessionDir)
+	if err != nil {
+		return err
+	}
+
+	sessions, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	spec, err := export.ParseSpec(specRaw)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := export.New(spec.Type)
+	if err != nil {
+		return err
+	}
+
+	records := make([]export.Session, 0, len(sessions))
+	for _, s := range sessions {
+		transcript, err := os.ReadFile(filepath.Join(outputDir, s.ID+".log"))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		records = append(records, export.Session{
+			ID:         s.ID,
+			Task:       s.Task,
+			Status:     s.Status.String(),
+			Started:    s.Started,
+			Transcript: transcript,
+		})
+	}
+
+	if err := exporter.Export(records, spec.Dest); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d session(s) to %s\n", len(records), spec.Dest)
+	return nil
+}
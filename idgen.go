@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// idLen is the number of leading hex characters of the digest used as a
+// session's short ID.
+const idLen = 10
+
+// genID computes a content-addressable session ID from a task's prompt,
+// cwd, model, and env, so that identical tasks produce identical IDs
+// across runs. It returns the short ID alongside the full sha256 digest
+// it was derived from.
+func genID(task Task) (id, digest string) {
+	h := sha256.New()
+	h.Write([]byte(normalizeTask(task.Prompt)))
+	h.Write([]byte{0})
+	h.Write([]byte(task.Cwd))
+	h.Write([]byte{0})
+	h.Write([]byte(task.Model))
+	h.Write([]byte{0})
+	h.Write([]byte(hashEnv(task.Env)))
+
+	sum := h.Sum(nil)
+	digest = hex.EncodeToString(sum)
+	return digest[:idLen], digest
+}
+
+// normalizeTask collapses surrounding whitespace so cosmetic differences
+// in a prompt don't change its ID.
+func normalizeTask(prompt string) string {
+	return strings.TrimSpace(prompt)
+}
+
+// hashEnv returns a stable digest of an env map regardless of iteration
+// order.
+func hashEnv(env map[string]string) string {
+	if len(env) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{'='})
+		h.Write([]byte(env[k]))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestGenIDDeterministic(t *testing.T) {
+	task := Task{
+		Prompt: "  do the thing  ",
+		Cwd:    "/work",
+		Model:  "claude-3",
+		Env:    map[string]string{"B": "2", "A": "1"},
+	}
+	reordered := Task{
+		Prompt: "do the thing",
+		Cwd:    "/work",
+		Model:  "claude-3",
+		Env:    map[string]string{"A": "1", "B": "2"},
+	}
+
+	id1, digest1 := genID(task)
+	id2, digest2 := genID(reordered)
+
+	if id1 != id2 || digest1 != digest2 {
+		t.Fatalf("genID() not deterministic across equivalent inputs: (%s, %s) vs (%s, %s)", id1, digest1, id2, digest2)
+	}
+	if len(id1) != idLen {
+		t.Fatalf("genID() id length = %d, want %d", len(id1), idLen)
+	}
+}
+
+func TestGenIDDistinctness(t *testing.T) {
+	base := Task{Prompt: "do the thing", Cwd: "/work", Model: "claude-3", Env: map[string]string{"A": "1"}}
+
+	variants := map[string]Task{
+		"prompt": {Prompt: "do the other thing", Cwd: base.Cwd, Model: base.Model, Env: base.Env},
+		"cwd":    {Prompt: base.Prompt, Cwd: "/elsewhere", Model: base.Model, Env: base.Env},
+		"model":  {Prompt: base.Prompt, Cwd: base.Cwd, Model: "claude-4", Env: base.Env},
+		"env":    {Prompt: base.Prompt, Cwd: base.Cwd, Model: base.Model, Env: map[string]string{"A": "2"}},
+	}
+
+	baseID, _ := genID(base)
+
+	for name, variant := range variants {
+		t.Run(name, func(t *testing.T) {
+			id, _ := genID(variant)
+			if id == baseID {
+				t.Fatalf("genID() did not change when %s differed", name)
+			}
+		})
+	}
+}
@@ -2,24 +2,40 @@ package main
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	// Only treat a leading "export" as the export subcommand when it's
+	// followed by a flag (e.g. "export -o ..."). A bare "export" with no
+	// further args is ambiguous with the legacy single-word-prompt usage
+	// ("conductor export" spawning a session whose task is "export"), so
+	// it falls through to that instead.
+	if len(os.Args) > 2 && os.Args[1] == "export" && strings.HasPrefix(os.Args[2], "-") {
+		if err := runExportCmd(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var (
 		taskFile = flag.String("f", "", "read tasks from file (- for stdin)")
+		dedup    = flag.String("dedup", string(dedupFork), "when a task's id already exists: skip, attach, or fork")
+		noCache  = flag.Bool("no-cache", false, "disable the follow-up transcript cache")
+		output   string
 		help     = flag.Bool("h", false, "show help")
 	)
+	flag.StringVar(&output, "o", "", "export sessions using an output spec, e.g. type=json,dest=sessions.json")
+	flag.StringVar(&output, "output", "", "same as -o")
 	flag.Parse()
 
 	if *help {
@@ -27,26 +43,46 @@ func main() {
 		return
 	}
 
+	mode := dedupMode(*dedup)
+	switch mode {
+	case dedupFork, dedupSkip, dedupAttach:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid -dedup mode %q (want skip, attach, or fork)\n", *dedup)
+		os.Exit(1)
+	}
+
 	tasks, err := parseTasks(*taskFile, flag.Args())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if len(tasks) == 0 {
-		if err := runTUI(); err != nil {
+	var wg sync.WaitGroup
+	if len(tasks) > 0 {
+		if err := spawnTasks(tasks, mode, *noCache, &wg); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		return
 	}
 
-	if err := spawnTasks(tasks); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	// Spawned tasks (and their pre/post hooks) print status lines and
+	// shell output straight to stdout/stderr, and must finish before we
+	// either export (or the export can run against sessions that are
+	// still Running and miss transcript data written after it) or hand
+	// the terminal to bubbletea, which puts those same fds in raw/alt-
+	// screen mode and would otherwise have its render corrupted by
+	// interleaved hook/status output.
+	wg.Wait()
+
+	if output != "" {
+		if err := runExport(output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	if err := runTUI(); err != nil {
+	if err := runTUI(*noCache); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -58,83 +94,68 @@ func usage() {
 Usage:
   conductor [options] "task1" "task2" "task3"
   conductor -f tasks.txt
+  conductor -f tasks.yaml
+  conductor export -o type=json,dest=sessions.json
   conductor (starts TUI for existing sessions)
 
 Options:
-  -f FILE    Read tasks from file (- for stdin)
+  -f FILE    Read tasks from file (- for stdin). A .yaml/.yml/.json
+             extension is parsed as a structured task manifest with
+             id, prompt, cwd, env, depends_on, pre, and post fields.
+  -dedup     When a task's content-addressable id already exists:
+             skip, attach (send it as a follow-up), or fork a new
+             id (default).
+  -o, --output  Export known sessions using an output spec instead of
+             opening the TUI, e.g. type=local,dest=./out,
+             type=tar,dest=-, type=json,dest=sessions.json, or
+             type=markdown,dest=report.md. Short forms ./out and -
+             are accepted too. Equivalent to "conductor export -o ...".
+  -no-cache  Disable the follow-up transcript cache, always
+             re-invoking Claude for an Attach follow-up.
   -h         Show help`)
 }
 
-func parseTasks(file string, args []string) ([]string, error) {
-	if file != "" {
-		var r io.Reader
-		if file == "-" {
-			r = os.Stdin
-		} else {
-			f, err := os.Open(file)
-			if err != nil {
-				return nil, err
-			}
-			defer f.Close()
-			r = f
+// parseTasks reads tasks either from the legacy \n\n\n-delimited plain
+// text format or, when file has a .yaml/.yml/.json extension, from a
+// structured manifest (see parseManifest).
+func parseTasks(file string, args []string) ([]Task, error) {
+	if file == "" {
+		tasks := make([]Task, 0, len(args))
+		for _, a := range args {
+			tasks = append(tasks, Task{Prompt: a})
 		}
-		data, err := io.ReadAll(r)
+		return tasks, nil
+	}
+
+	var r io.Reader
+	if file == "-" {
+		r = os.Stdin
+	} else {
+		f, err := os.Open(file)
 		if err != nil {
 			return nil, err
 		}
-		return strings.Split(string(data), "\n\n\n"), nil
-	}
-	return args, nil
-}
-
-func spawnTasks(tasks []string) error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return err
+		defer f.Close()
+		r = f
 	}
 
-	baseDir := filepath.Join(home, ".conductor")
-	sessionDir := filepath.Join(baseDir, "sessions")
-	outputDir := filepath.Join(baseDir, "outputs")
-
-	store, err := NewStore(sessionDir)
+	data, err := io.ReadAll(r)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	mgr, err := NewManager(outputDir)
-	if err != nil {
-		return err
+	if isManifestFile(file) {
+		return parseManifest(file, data)
 	}
 
-	for _, task := range tasks {
-		task = strings.TrimSpace(task)
-		if task == "" {
-			continue
-		}
-
-		sess := &Session{
-			ID:      genID(),
-			Task:    task,
-			Status:  Running,
-			Started: time.Now(),
-		}
-
-		if err := mgr.Spawn(sess); err != nil {
-			return err
-		}
-
-		if err := store.Save(sess); err != nil {
-			return err
-		}
-
-		fmt.Printf("Started: %s (%s)\n", truncate(task, 50), sess.ID)
+	var tasks []Task
+	for _, prompt := range strings.Split(string(data), "\n\n\n") {
+		tasks = append(tasks, Task{Prompt: prompt})
 	}
-
-	return nil
+	return tasks, nil
 }
 
-func runTUI() error {
+func runTUI(noCache bool) error {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return err
@@ -153,6 +174,7 @@ func runTUI() error {
 	if err != nil {
 		return err
 	}
+	mgr.NoCache = noCache
 
 	for {
 		m := New(store, mgr)
@@ -189,9 +211,3 @@ func runTUI() error {
 
 	return nil
 }
-
-func genID() string {
-	b := make([]byte, 4)
-	rand.Read(b)
-	return hex.EncodeToString(b)
-}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+
+	"github.com/brittonhayes/claude-conductor/cache"
+)
+
+// defaultCacheMaxSize bounds the on-disk follow-up transcript cache.
+const defaultCacheMaxSize = 256 << 20 // 256MiB
+
+// Manager launches and attaches to Claude sessions, writing their
+// transcripts under outputDir.
+type Manager struct {
+	outputDir string
+	cache     *cache.Cache
+
+	// NoCache disables serving Attach follow-ups from the transcript
+	// cache, forcing every call to re-invoke Claude.
+	NoCache bool
+
+	mu    sync.Mutex
+	procs map[string]*process
+}
+
+type process struct {
+	cmd  *exec.Cmd
+	log  *os.File
+	once sync.Once
+	err  error
+}
+
+func NewManager(outputDir string) (*Manager, error) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	cacheDir := filepath.Join(filepath.Dir(outputDir), "cache")
+	c, err := cache.New(cacheDir, defaultCacheMaxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{outputDir: outputDir, cache: c, procs: make(map[string]*process)}, nil
+}
+
+func (m *Manager) Spawn(sess *Session) error {
+	f, err := os.Create(filepath.Join(m.outputDir, sess.ID+".log"))
+	if err != nil {
+		return err
+	}
+
+	args := []string{"-p", sess.Task}
+	if sess.Model != "" {
+		args = append(args, "--model", sess.Model)
+	}
+
+	cmd := exec.Command("claude", args...)
+	cmd.Dir = sess.Cwd
+	cmd.Env = os.Environ()
+	for k, v := range sess.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = f
+	cmd.Stderr = f
+	if err := cmd.Start(); err != nil {
+		f.Close()
+		return err
+	}
+
+	m.mu.Lock()
+	m.procs[sess.ID] = &process{cmd: cmd, log: f}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Wait blocks until the session's spawned process exits, closing its
+// transcript once it does. It is safe to call more than once (e.g. from
+// a post-hook goroutine and a dependent task) or concurrently: every
+// caller blocks until the process has exited and observes the same
+// result.
+func (m *Manager) Wait(id string) error {
+	m.mu.Lock()
+	p := m.procs[id]
+	m.mu.Unlock()
+
+	if p == nil {
+		return fmt.Errorf("manager: no running process for session %s", id)
+	}
+
+	p.once.Do(func() {
+		defer p.log.Close()
+		p.err = p.cmd.Wait()
+	})
+	return p.err
+}
+
+// Attach sends followup to sess, serving an identical prior follow-up
+// from the transcript cache when possible. The response is appended to
+// the session's transcript only the first time it's produced; a cache
+// hit (e.g. the TUI re-entered after a crash, or a manifest run retried)
+// must not duplicate it in the transcript.
+func (m *Manager) Attach(ctx context.Context, sess *Session, followup string) error {
+	produce := func() (io.ReadCloser, error) {
+		return m.runAttach(ctx, sess, followup)
+	}
+
+	if m.NoCache {
+		rc, err := produce()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return err
+		}
+		return m.appendLog(sess.ID, data)
+	}
+
+	data, hit, err := m.cache.GetOrCreate(cache.Key(sess.ID, followup), produce)
+	if err != nil {
+		return err
+	}
+	if hit {
+		return nil
+	}
+
+	return m.appendLog(sess.ID, data)
+}
+
+// runAttach invokes Claude for a follow-up and returns its combined
+// output.
+func (m *Manager) runAttach(ctx context.Context, sess *Session, followup string) (io.ReadCloser, error) {
+	args := []string{"-p", followup, "--resume", sess.ID}
+	if sess.Model != "" {
+		args = append(args, "--model", sess.Model)
+	}
+
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	cmd.Dir = sess.Cwd
+	cmd.Env = os.Environ()
+	for k, v := range sess.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(out.Bytes())), nil
+}
+
+func (m *Manager) appendLog(id string, data []byte) error {
+	f, err := os.OpenFile(filepath.Join(m.outputDir, id+".log"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
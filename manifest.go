@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Task describes a single unit of work to spawn, either a bare prompt
+// string (legacy format) or a fully specified manifest entry.
+type Task struct {
+	ID        string            `json:"id,omitempty" yaml:"id,omitempty"`
+	Prompt    string            `json:"prompt" yaml:"prompt"`
+	Cwd       string            `json:"cwd,omitempty" yaml:"cwd,omitempty"`
+	Model     string            `json:"model,omitempty" yaml:"model,omitempty"`
+	Env       map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	DependsOn []string          `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Pre       []string          `json:"pre,omitempty" yaml:"pre,omitempty"`
+	Post      []string          `json:"post,omitempty" yaml:"post,omitempty"`
+}
+
+// manifest is the on-disk shape of a YAML/JSON task file.
+type manifest struct {
+	Tasks []Task `json:"tasks" yaml:"tasks"`
+}
+
+// isManifestFile reports whether file should be parsed as a structured
+// YAML/JSON manifest rather than the legacy \n\n\n-delimited format.
+func isManifestFile(file string) bool {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseManifest decodes a YAML or JSON task manifest, assigns default IDs,
+// and topologically sorts the result by depends_on so spawnTasks can run
+// tasks strictly after their dependencies.
+func parseManifest(file string, data []byte) ([]Task, error) {
+	var m manifest
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("parse manifest: %w", err)
+		}
+	default:
+		if err := yaml.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("parse manifest: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(m.Tasks))
+	for i := range m.Tasks {
+		if m.Tasks[i].ID == "" {
+			m.Tasks[i].ID = fmt.Sprintf("task-%d", i+1)
+		}
+		if seen[m.Tasks[i].ID] {
+			return nil, fmt.Errorf("parse manifest: duplicate task id %q", m.Tasks[i].ID)
+		}
+		seen[m.Tasks[i].ID] = true
+	}
+
+	return topoSortTasks(m.Tasks)
+}
+
+// topoSortTasks orders tasks so that every task appears after all of its
+// depends_on entries, returning an error if the graph contains a cycle.
+func topoSortTasks(tasks []Task) ([]Task, error) {
+	byID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(tasks))
+	var order []Task
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("parse manifest: dependency cycle detected at task %q", id)
+		}
+		state[id] = visiting
+		for _, dep := range byID[id].DependsOn {
+			if _, ok := byID[dep]; !ok {
+				return fmt.Errorf("parse manifest: task %q depends on unknown task %q", id, dep)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		order = append(order, byID[id])
+		return nil
+	}
+
+	for _, t := range tasks {
+		if err := visit(t.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+// runHook runs a single pre/post shell command in cwd with env merged
+// into the current process environment.
+func runHook(command, cwd string, env map[string]string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Dir = cwd
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopoSortTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []Task
+		wantErr string
+		wantIDs []string
+	}{
+		{
+			name: "orders dependents after dependencies",
+			tasks: []Task{
+				{ID: "c", DependsOn: []string{"b"}},
+				{ID: "b", DependsOn: []string{"a"}},
+				{ID: "a"},
+			},
+			wantIDs: []string{"a", "b", "c"},
+		},
+		{
+			name: "no dependencies keeps input order",
+			tasks: []Task{
+				{ID: "a"},
+				{ID: "b"},
+			},
+			wantIDs: []string{"a", "b"},
+		},
+		{
+			name: "cycle is rejected",
+			tasks: []Task{
+				{ID: "a", DependsOn: []string{"b"}},
+				{ID: "b", DependsOn: []string{"a"}},
+			},
+			wantErr: "dependency cycle",
+		},
+		{
+			name: "unknown dependency is rejected",
+			tasks: []Task{
+				{ID: "a", DependsOn: []string{"missing"}},
+			},
+			wantErr: "unknown task",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := topoSortTasks(tt.tasks)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("topoSortTasks() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("topoSortTasks() unexpected error: %v", err)
+			}
+
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("topoSortTasks() returned %d tasks, want %d", len(got), len(tt.wantIDs))
+			}
+			for i, id := range tt.wantIDs {
+				if got[i].ID != id {
+					t.Errorf("order[%d] = %q, want %q", i, got[i].ID, id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Model is the bubbletea TUI model listing known sessions.
+type Model struct {
+	store    *Store
+	mgr      *Manager
+	sessions []*Session
+	cursor   int
+	attach   *Session
+}
+
+func New(store *Store, mgr *Manager) Model {
+	sessions, _ := store.List()
+	return Model{store: store, mgr: mgr, sessions: sessions}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.sessions)-1 {
+				m.cursor++
+			}
+		case "enter":
+			if m.cursor < len(m.sessions) {
+				m.attach = m.sessions[m.cursor]
+			}
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m Model) View() string {
+	var out string
+	for i, sess := range m.sessions {
+		cursor := " "
+		if i == m.cursor {
+			cursor = ">"
+		}
+		out += cursor + " " + sess.ID + " " + sess.Status.String() + " " + truncate(sess.Task, 50) + "\n"
+	}
+	return out
+}
+
+// Attach returns the session the user selected to follow up on, if any.
+func (m Model) Attach() *Session {
+	return m.attach
+}
@@ -0,0 +1,37 @@
+package main
+
+import "time"
+
+// Status represents the lifecycle state of a Session.
+type Status int
+
+const (
+	Running Status = iota
+	Completed
+	Failed
+)
+
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Completed:
+		return "completed"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Session tracks a single spawned Claude session.
+type Session struct {
+	ID      string
+	Digest  string
+	Task    string
+	Cwd     string
+	Model   string
+	Env     map[string]string
+	Status  Status
+	Started time.Time
+}
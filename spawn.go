@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dedupMode controls what spawnTasks does when a task's content-addressable
+// ID already exists in the Store.
+type dedupMode string
+
+const (
+	dedupFork   dedupMode = "fork"
+	dedupSkip   dedupMode = "skip"
+	dedupAttach dedupMode = "attach"
+)
+
+// taskOutcome is the terminal result of running one task: either the
+// session it resolved to, or the error that kept it from completing.
+type taskOutcome struct {
+	sess *Session
+	err  error
+}
+
+// taskFuture lets dependent tasks block on a task's outcome without
+// touching its Session directly, so no two goroutines ever race on the
+// same Session's fields. state is only written once, before ready is
+// closed, so readers that have observed ready closed may read it freely.
+type taskFuture struct {
+	ready chan struct{}
+	state taskOutcome
+}
+
+// sessionClaim is the outcome of a contended content-addressable id:
+// whichever goroutine doesn't win the claim blocks on ready and then
+// reads sess, which is only written once, before ready is closed. sess
+// is a snapshot shared by every waiter, so a waiter copies it before
+// changing any field.
+type sessionClaim struct {
+	ready chan struct{}
+	sess  *Session
+}
+
+// idRegistry serializes "does this id already exist" resolution across
+// the goroutines spawnTasks launches. store.Load alone only sees
+// sessions persisted by earlier runs, so two tasks that hash to the
+// same id within one run would both observe "not found" and both fall
+// through to Spawn; idRegistry makes the check-then-reserve atomic for
+// the lifetime of this run.
+type idRegistry struct {
+	mu     sync.Mutex
+	claims map[string]*sessionClaim
+}
+
+func newIDRegistry() *idRegistry {
+	return &idRegistry{claims: make(map[string]*sessionClaim)}
+}
+
+// reserve claims id for the caller if nobody else has claimed it yet in
+// this run (falling back to store for sessions persisted by earlier
+// runs). When owner is false, the caller must wait on claim.ready and
+// then read claim.sess; when owner is true, the caller is responsible
+// for eventually calling claim.resolve with the session it settles on.
+func (r *idRegistry) reserve(store *Store, id string) (claim *sessionClaim, owner bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c, ok := r.claims[id]; ok {
+		return c, false
+	}
+
+	c := &sessionClaim{ready: make(chan struct{})}
+	r.claims[id] = c
+
+	if existing, err := store.Load(id); err == nil {
+		c.sess = existing
+		close(c.ready)
+		return c, false
+	}
+
+	return c, true
+}
+
+// resolve publishes a copy of the session an owning goroutine settled on
+// for its claimed id and wakes any goroutine blocked in reserve. The
+// owner keeps its own *Session, so its later Status updates are not
+// seen by waiters.
+func (c *sessionClaim) resolve(sess *Session) {
+	snapshot := *sess
+	c.sess = &snapshot
+	close(c.ready)
+}
+
+// spawnTasks launches every task's pipeline (wait for depends_on, run
+// pre hooks, spawn or dedup, wait for completion, run post hooks) in its
+// own goroutine tracked by wg, so one task's dependency chain never
+// blocks an unrelated task's pipeline. It returns once every task has
+// been launched — not once they've finished — so callers must wg.Wait()
+// before the process exits, or queued post hooks can be silently
+// dropped.
+func spawnTasks(tasks []Task, mode dedupMode, noCache bool, wg *sync.WaitGroup) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+
+	baseDir := filepath.Join(home, ".conductor")
+	sessionDir := filepath.Join(baseDir, "sessions")
+	outputDir := filepath.Join(baseDir, "outputs")
+
+	store, err := NewStore(sessionDir)
+	if err != nil {
+		return err
+	}
+
+	mgr, err := NewManager(outputDir)
+	if err != nil {
+		return err
+	}
+	mgr.NoCache = noCache
+
+	futures := make(map[string]*taskFuture, len(tasks))
+	for _, t := range tasks {
+		if t.ID != "" {
+			futures[t.ID] = &taskFuture{ready: make(chan struct{})}
+		}
+	}
+
+	ids := newIDRegistry()
+
+	for _, task := range tasks {
+		task := task
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runTaskPipeline(mgr, store, task, mode, futures, ids)
+		}()
+	}
+
+	return nil
+}
+
+// runTaskPipeline runs one task end to end and, if the task has an ID,
+// publishes the outcome to its future for any tasks that depend on it.
+func runTaskPipeline(mgr *Manager, store *Store, task Task, mode dedupMode, futures map[string]*taskFuture, ids *idRegistry) {
+	outcome := doRunTask(mgr, store, task, mode, futures, ids)
+
+	if f, ok := futures[task.ID]; ok {
+		f.state = outcome
+		close(f.ready)
+	}
+
+	if outcome.err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", outcome.err)
+	}
+}
+
+// doRunTask waits for task's dependencies to finish, runs its pre hooks,
+// resolves it to a session (spawning a new one, or skipping/attaching
+// per mode when its content-addressable id already exists), waits for
+// that session to leave Running, and runs its post hooks — including
+// when dedupSkip or dedupAttach resolves it to an existing session
+// instead of spawning, since that's a Running-to-settled transition
+// for this task just as much as a fresh Spawn is.
+func doRunTask(mgr *Manager, store *Store, task Task, mode dedupMode, futures map[string]*taskFuture, ids *idRegistry) taskOutcome {
+	task.Prompt = strings.TrimSpace(task.Prompt)
+	if task.Prompt == "" {
+		return taskOutcome{}
+	}
+
+	for _, dep := range task.DependsOn {
+		f, ok := futures[dep]
+		if !ok {
+			continue
+		}
+		<-f.ready
+		if f.state.err != nil {
+			return taskOutcome{err: fmt.Errorf("task %q: dependency %q: %w", task.ID, dep, f.state.err)}
+		}
+	}
+
+	for _, cmd := range task.Pre {
+		if err := runHook(cmd, task.Cwd, task.Env); err != nil {
+			return taskOutcome{err: fmt.Errorf("pre hook for task %q: %w", task.ID, err)}
+		}
+	}
+
+	id, digest := genID(task)
+
+	claim, owner := ids.reserve(store, id)
+	if !owner {
+		<-claim.ready
+		existing := *claim.sess
+
+		switch mode {
+		case dedupSkip:
+			fmt.Printf("Skipped (already exists): %s (%s)\n", truncate(task.Prompt, 50), id)
+			runPostHooks(task)
+			return taskOutcome{sess: &existing}
+		case dedupAttach:
+			fmt.Printf("Attaching follow-up to existing session: %s (%s)\n", truncate(task.Prompt, 50), id)
+			if err := mgr.Attach(context.Background(), &existing, task.Prompt); err != nil {
+				return taskOutcome{err: fmt.Errorf("attach follow-up for task %q: %w", task.ID, err)}
+			}
+			existing.Status = Completed
+			store.Save(&existing)
+			runPostHooks(task)
+			return taskOutcome{sess: &existing}
+		case dedupFork:
+			id, claim = forkID(ids, store, id)
+		}
+	}
+
+	sess := &Session{
+		ID:      id,
+		Digest:  digest,
+		Task:    task.Prompt,
+		Cwd:     task.Cwd,
+		Model:   task.Model,
+		Env:     task.Env,
+		Status:  Running,
+		Started: time.Now(),
+	}
+
+	if err := mgr.Spawn(sess); err != nil {
+		claim.resolve(sess)
+		return taskOutcome{err: err}
+	}
+	if err := store.Save(sess); err != nil {
+		claim.resolve(sess)
+		return taskOutcome{err: err}
+	}
+	claim.resolve(sess)
+
+	fmt.Printf("Started: %s (%s)\n", truncate(task.Prompt, 50), sess.ID)
+
+	waitErr := mgr.Wait(sess.ID)
+	if waitErr != nil {
+		sess.Status = Failed
+	} else {
+		sess.Status = Completed
+	}
+	store.Save(sess)
+
+	runPostHooks(task)
+
+	if waitErr != nil {
+		return taskOutcome{sess: sess, err: fmt.Errorf("session %s did not complete successfully: %w", sess.ID, waitErr)}
+	}
+	return taskOutcome{sess: sess}
+}
+
+// runPostHooks runs task's post hooks, logging (but not failing the
+// task on) any error, since by the time they run the task has already
+// settled into a terminal status.
+func runPostHooks(task Task) {
+	for _, cmd := range task.Post {
+		if err := runHook(cmd, task.Cwd, task.Env); err != nil {
+			fmt.Fprintf(os.Stderr, "post hook for task %q: %v\n", task.ID, err)
+		}
+	}
+}
+
+// forkID appends an incrementing suffix to id, reserving each candidate
+// through ids, until it finds one that isn't already claimed by a
+// sibling task in this run or persisted in store — so identical tasks
+// can still be run side by side under -dedup=fork.
+func forkID(ids *idRegistry, store *Store, id string) (string, *sessionClaim) {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", id, n)
+		if claim, owner := ids.reserve(store, candidate); owner {
+			return candidate, claim
+		}
+	}
+}